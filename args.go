@@ -0,0 +1,73 @@
+package main
+
+import "strings"
+
+// options holds the flags and operands we recognize ourselves. We parse
+// args by hand rather than with the flag package so that --use-ls can
+// still forward arbitrary, unrecognized ls-style flags (-la, etc.)
+// straight through to the real ls binary. rawArgs holds what's left
+// after stripping every flag (and its value) we recognized ourselves,
+// since -x/-l/--theme/etc. are ours, not the real ls's, and forwarding
+// them corrupts its output (see listDirViaLS).
+type options struct {
+	useLS      bool
+	across     bool
+	long       bool
+	human      bool
+	git        bool
+	dir        string
+	theme      string
+	config     string
+	hyperlinks string
+	rawArgs    []string
+}
+
+func parseArgs(args []string) *options {
+	opts := &options{dir: "."}
+
+	var operands []string
+	var passthrough []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--use-ls":
+			opts.useLS = true
+		case a == "-x":
+			opts.across = true
+		case a == "-l":
+			opts.long = true
+		case a == "-h":
+			opts.human = true
+		case a == "--git":
+			opts.git = true
+		case a == "--theme" && i+1 < len(args):
+			i++
+			opts.theme = args[i]
+		case strings.HasPrefix(a, "--theme="):
+			opts.theme = strings.TrimPrefix(a, "--theme=")
+		case a == "--config" && i+1 < len(args):
+			i++
+			opts.config = args[i]
+		case strings.HasPrefix(a, "--config="):
+			opts.config = strings.TrimPrefix(a, "--config=")
+		case a == "--hyperlinks" && i+1 < len(args):
+			i++
+			opts.hyperlinks = args[i]
+		case strings.HasPrefix(a, "--hyperlinks="):
+			opts.hyperlinks = strings.TrimPrefix(a, "--hyperlinks=")
+		case strings.HasPrefix(a, "-"):
+			// unrecognized flag; forward it to listDirViaLS
+			passthrough = append(passthrough, a)
+		default:
+			operands = append(operands, a)
+			passthrough = append(passthrough, a)
+		}
+	}
+
+	if len(operands) > 0 {
+		opts.dir = operands[0]
+	}
+	opts.rawArgs = passthrough
+
+	return opts
+}
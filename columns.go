@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// printColumns lays files out in a down-then-across grid, picking the
+// widest column count that still fits the terminal.
+func printColumns(files []string) {
+	termWidth, err := getTerminalWidth()
+	if err != nil {
+		// If we can't determine, default to 80
+		termWidth = 80
+	}
+
+	if len(files) == 0 {
+		return
+	}
+
+	// We'll try to find the optimal number of columns.
+	// Start from the maximum possible columns and go down until we find a fit.
+	// Maximum possible columns can't be more than number of files.
+	// Also capped at termWidth, since every column is at least one cell wide.
+	maxPossibleCols := len(files)
+	if maxPossibleCols > termWidth {
+		maxPossibleCols = termWidth
+	}
+	if maxPossibleCols < 1 {
+		maxPossibleCols = 1
+	}
+
+	padding := 2
+
+	bestCols := 1
+
+	// Try from maxPossibleCols down to 1
+	for tryCols := maxPossibleCols; tryCols > 0; tryCols-- {
+		rows := (len(files) + tryCols - 1) / tryCols
+
+		// Compute column widths for this layout
+		colWidths := make([]int, tryCols)
+		totalWidth := 0
+		for col := 0; col < tryCols; col++ {
+			maxW := 0
+			for row := 0; row < rows; row++ {
+				index := col*rows + row
+				if index >= len(files) {
+					break
+				}
+				displayLen := displayWidth(stripANSI(files[index]))
+				if displayLen > maxW {
+					maxW = displayLen
+				}
+			}
+			colWidths[col] = maxW
+		}
+
+		for i, w := range colWidths {
+			totalWidth += w
+			if i < tryCols-1 {
+				totalWidth += padding
+			}
+		}
+
+		if totalWidth <= termWidth {
+			// This fits, record it and break (since we are going top-down from largest cols)
+			bestCols = tryCols
+			break
+		}
+	}
+
+	// Now print using bestCols in vertical layout
+	rows := (len(files) + bestCols - 1) / bestCols
+	colWidths := make([]int, bestCols)
+	for col := 0; col < bestCols; col++ {
+		maxW := 0
+		for row := 0; row < rows; row++ {
+			index := col*rows + row
+			if index >= len(files) {
+				break
+			}
+			displayLen := displayWidth(stripANSI(files[index]))
+			if displayLen > maxW {
+				maxW = displayLen
+			}
+		}
+		colWidths[col] = maxW
+	}
+
+	for row := 0; row < rows; row++ {
+		var buffer bytes.Buffer
+		for col := 0; col < bestCols; col++ {
+			index := col*rows + row
+			if index >= len(files) {
+				break
+			}
+			f := files[index]
+			displayLen := displayWidth(stripANSI(f))
+			buffer.WriteString(f)
+			if col < bestCols-1 {
+				spaces := colWidths[col] - displayLen + padding
+				if spaces < 1 {
+					spaces = 1
+				}
+				buffer.WriteString(strings.Repeat(" ", spaces))
+			}
+		}
+		fmt.Println(buffer.String())
+	}
+}
+
+// printColumnsAcross lays files out across-first (fill each row
+// left-to-right before moving to the next), picking the widest column
+// count that still fits the terminal. Column widths are computed
+// row-major rather than printColumns's column-major indexing.
+func printColumnsAcross(files []string) {
+	termWidth, err := getTerminalWidth()
+	if err != nil {
+		termWidth = 80
+	}
+
+	if len(files) == 0 {
+		return
+	}
+
+	maxPossibleCols := len(files)
+	if maxPossibleCols > termWidth {
+		maxPossibleCols = termWidth
+	}
+	if maxPossibleCols < 1 {
+		maxPossibleCols = 1
+	}
+
+	padding := 2
+	bestCols := 1
+
+	for tryCols := maxPossibleCols; tryCols > 0; tryCols-- {
+		colWidths := acrossColWidths(files, tryCols)
+		totalWidth := 0
+		for i, w := range colWidths {
+			totalWidth += w
+			if i < tryCols-1 {
+				totalWidth += padding
+			}
+		}
+		if totalWidth <= termWidth {
+			bestCols = tryCols
+			break
+		}
+	}
+
+	colWidths := acrossColWidths(files, bestCols)
+	for start := 0; start < len(files); start += bestCols {
+		var buffer bytes.Buffer
+		for col := 0; col < bestCols; col++ {
+			index := start + col
+			if index >= len(files) {
+				break
+			}
+			f := files[index]
+			displayLen := displayWidth(stripANSI(f))
+			buffer.WriteString(f)
+			if col < bestCols-1 && index < len(files)-1 {
+				spaces := colWidths[col] - displayLen + padding
+				if spaces < 1 {
+					spaces = 1
+				}
+				buffer.WriteString(strings.Repeat(" ", spaces))
+			}
+		}
+		fmt.Println(buffer.String())
+	}
+}
+
+func acrossColWidths(files []string, cols int) []int {
+	colWidths := make([]int, cols)
+	for i, f := range files {
+		col := i % cols
+		w := displayWidth(stripANSI(f))
+		if w > colWidths[col] {
+			colWidths[col] = w
+		}
+	}
+	return colWidths
+}
+
+func getTerminalWidth() (int, error) {
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w < 1 {
+		return 0, fmt.Errorf("could not determine terminal width")
+	}
+	return w, nil
+}
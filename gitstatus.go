@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitStatus holds the raw two-character index/worktree status pair from
+// `git status --porcelain=v1` (e.g. staged 'A' + worktree 'M' stays
+// distinguishable as {'A', 'M'}), rather than collapsing both into a
+// single category. A space means "no change" in that slot.
+type gitStatus [2]byte
+
+var gitClean = gitStatus{' ', ' '}
+
+// gitStatusCharColors colors each status character independently,
+// reusing the existing palette rather than growing the theme config's
+// schema. ' ' (no change in that slot) is rendered as '-'.
+var gitStatusCharColors = map[byte]string{
+	'M': colors["fadedyellow"],
+	'A': colors["green"],
+	'D': colors["fadedred"],
+	'R': colors["fadedmagenta"],
+	'C': colors["fadedmagenta"],
+	'U': colors["fadedred"],
+	'?': colors["gray"],
+	'!': colors["fadedgray"],
+	' ': colors["fadedgray"],
+}
+
+// statusCharSeverity ranks a single status character from least to
+// most noteworthy, used to pick the "worst" entry when aggregating a
+// directory's contents.
+func statusCharSeverity(c byte) int {
+	switch c {
+	case ' ':
+		return 0
+	case '!':
+		return 1
+	case '?':
+		return 2
+	case 'D':
+		return 4
+	default:
+		return 3
+	}
+}
+
+func statusSeverity(st gitStatus) int {
+	if a, b := statusCharSeverity(st[0]), statusCharSeverity(st[1]); a > b {
+		return a
+	} else {
+		return b
+	}
+}
+
+// gitColumnEnabled and gitStatusMap are set once by initGitStatus and
+// reused for every entry, so a single `git status` invocation serves
+// the whole listing.
+var (
+	gitColumnEnabled bool
+	gitStatusMap     map[string]gitStatus
+)
+
+// initGitStatus enables the --git status column when dir is inside a
+// Git working tree and the git binary is available, degrading silently
+// (no column) otherwise.
+func initGitStatus(opts *options) {
+	if !opts.git {
+		return
+	}
+	statuses, ok := loadGitStatus(opts.dir)
+	if !ok {
+		return
+	}
+	gitColumnEnabled = true
+	gitStatusMap = statuses
+}
+
+// gitStatusPrefix renders the colored two-character status indicator
+// (index char + worktree char, e.g. "AM", "--", "??") for name, or ""
+// when the --git column is disabled.
+func gitStatusPrefix(name string, isDir bool) string {
+	if !gitColumnEnabled {
+		return ""
+	}
+	st, _ := statusFor(gitStatusMap, name, isDir)
+	return renderStatusChar(st[0]) + renderStatusChar(st[1]) + " "
+}
+
+func renderStatusChar(c byte) string {
+	display := c
+	if display == ' ' {
+		display = '-'
+	}
+	return gitStatusCharColors[c] + string(display) + "\033[0m"
+}
+
+// findRepoRoot walks up from dir looking for a ".git" entry.
+func findRepoRoot(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(abs, ".git")); err == nil {
+			return abs, true
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return "", false
+		}
+		abs = parent
+	}
+}
+
+// loadGitStatus runs a single `git status --porcelain=v1 -z --ignored`
+// for dir's repository and returns per-path statuses keyed relative to
+// dir. The bool return is false when dir isn't in a repo, or git is
+// missing/fails, so callers can degrade silently.
+func loadGitStatus(dir string) (map[string]gitStatus, bool) {
+	root, ok := findRepoRoot(dir)
+	if !ok {
+		return nil, false
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, false
+	}
+
+	cmd := exec.Command("git", "status", "--porcelain=v1", "-z", "--ignored")
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, false
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, false
+	}
+
+	statuses := map[string]gitStatus{}
+	trimmed := strings.TrimRight(string(out), "\x00")
+	if trimmed == "" {
+		return statuses, true
+	}
+
+	tokens := strings.Split(trimmed, "\x00")
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if len(tok) < 4 {
+			continue
+		}
+		x, y, path := tok[0], tok[1], tok[3:]
+		if x == 'R' || x == 'C' || y == 'R' || y == 'C' {
+			i++ // skip the accompanying original-path record
+		}
+
+		rel, err := filepath.Rel(absDir, filepath.Join(root, path))
+		if err != nil {
+			continue
+		}
+		statuses[rel] = gitStatus{x, y}
+	}
+
+	return statuses, true
+}
+
+// statusFor looks up name's status, or for a directory, the status of
+// the worst entry among any path it contains.
+func statusFor(statuses map[string]gitStatus, name string, isDir bool) (gitStatus, bool) {
+	if st, ok := statuses[name]; ok {
+		return st, true
+	}
+	if !isDir {
+		return gitClean, false
+	}
+
+	prefix := name + string(filepath.Separator)
+	best := gitClean
+	bestSeverity := -1
+	found := false
+	for path, st := range statuses {
+		if strings.HasPrefix(path, prefix) {
+			found = true
+			if sev := statusSeverity(st); sev > bestSeverity {
+				bestSeverity = sev
+				best = st
+			}
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// hyperlinksEnabled, hyperlinkHost, and hyperlinkBaseDir are resolved
+// once at startup by initHyperlinks and reused for every entry, rather
+// than re-resolving the absolute path/hostname per file.
+var (
+	hyperlinksEnabled bool
+	hyperlinkHost     string
+	hyperlinkBaseDir  string
+)
+
+// termProgramAllowlist names $TERM_PROGRAM values known to render OSC 8
+// hyperlinks, used by --hyperlinks=auto.
+var termProgramAllowlist = map[string]bool{
+	"iTerm.app": true,
+	"WezTerm":   true,
+	"vscode":    true,
+	"Hyper":     true,
+}
+
+// supportsHyperlinks applies the --hyperlinks=auto heuristic: a known
+// $TERM_PROGRAM, Kitty (via $KITTY_WINDOW_ID), or a VTE-based terminal
+// (via $VTE_VERSION, e.g. GNOME Terminal).
+func supportsHyperlinks() bool {
+	if termProgramAllowlist[os.Getenv("TERM_PROGRAM")] {
+		return true
+	}
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	if os.Getenv("VTE_VERSION") != "" {
+		return true
+	}
+	return false
+}
+
+// initHyperlinks resolves opts.hyperlinks ("auto", "always", "never")
+// and, if hyperlinks end up enabled, the absolute listing directory and
+// hostname used to build every entry's file:// URI.
+func initHyperlinks(opts *options) error {
+	var enabled bool
+	switch opts.hyperlinks {
+	case "", "auto":
+		enabled = supportsHyperlinks()
+	case "always":
+		enabled = true
+	case "never":
+		enabled = false
+	default:
+		return fmt.Errorf("unknown --hyperlinks value %q (want auto, always, or never)", opts.hyperlinks)
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	abs, err := filepath.Abs(opts.dir)
+	if err != nil {
+		return fmt.Errorf("resolving absolute path for %s: %w", opts.dir, err)
+	}
+
+	host, _ := os.Hostname() // empty host is valid per the OSC 8 spec
+
+	hyperlinksEnabled = true
+	hyperlinkHost = host
+	hyperlinkBaseDir = abs
+	return nil
+}
+
+// wrapHyperlink wraps rendered (the already-colorized display text for
+// name) in an OSC 8 hyperlink pointing at name's absolute path, when
+// hyperlinks are enabled. The wrapper is zero-width as far as
+// stripANSI/displayWidth are concerned, so column alignment is
+// unaffected.
+func wrapHyperlink(name, rendered string) string {
+	if !hyperlinksEnabled {
+		return rendered
+	}
+	target := filepath.Join(hyperlinkBaseDir, name)
+	uri := "file://" + hyperlinkHost + target
+	return "\x1b]8;;" + uri + "\x1b\\" + rendered + "\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var (
+	userNameCache  = map[uint32]string{}
+	groupNameCache = map[uint32]string{}
+)
+
+// lookupUser resolves a uid to a username, caching per uid. A lookup
+// failure falls back to (and caches) the numeric id, same as ls.
+func lookupUser(uid uint32) string {
+	if name, ok := userNameCache[uid]; ok {
+		return name
+	}
+	name := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	userNameCache[uid] = name
+	return name
+}
+
+// lookupGroup resolves a gid to a group name, caching per gid.
+func lookupGroup(gid uint32) string {
+	if name, ok := groupNameCache[gid]; ok {
+		return name
+	}
+	name := strconv.FormatUint(uint64(gid), 10)
+	if g, err := user.LookupGroupId(name); err == nil {
+		name = g.Name
+	}
+	groupNameCache[gid] = name
+	return name
+}
+
+// longEntry is one row of -l output before column widths are known.
+type longEntry struct {
+	mode    string
+	nlink   string
+	owner   string
+	group   string
+	size    string
+	mtime   string
+	display string // colorized name + -F classifier (+ " -> target")
+}
+
+// printLongListing renders dir's entries one per line with mode, link
+// count, owner, group, size, and mtime columns ahead of the colorized
+// name, mirroring `ls -l`. Numeric columns are right-aligned, owner/
+// group/name are left-aligned.
+func printLongListing(dir string, human bool) error {
+	names, err := orderedEntries(dir)
+	if err != nil {
+		return err
+	}
+
+	lsc := loadLSColors()
+	rows := make([]longEntry, 0, len(names))
+	for _, name := range names {
+		row, err := buildLongEntry(dir, name, lsc, human)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	var nlinkW, ownerW, groupW, sizeW int
+	for _, r := range rows {
+		nlinkW = maxInt(nlinkW, displayWidth(r.nlink))
+		ownerW = maxInt(ownerW, displayWidth(r.owner))
+		groupW = maxInt(groupW, displayWidth(r.group))
+		sizeW = maxInt(sizeW, displayWidth(r.size))
+	}
+
+	for _, r := range rows {
+		fmt.Printf("%s %s %s %s %s %s %s\n",
+			r.mode,
+			padLeft(r.nlink, nlinkW),
+			padRight(r.owner, ownerW),
+			padRight(r.group, groupW),
+			padLeft(r.size, sizeW),
+			r.mtime,
+			r.display,
+		)
+	}
+	return nil
+}
+
+func buildLongEntry(dir, name string, lsc lsColorSet, human bool) (longEntry, error) {
+	full := filepath.Join(dir, name)
+	info, err := os.Lstat(full)
+	if err != nil {
+		return longEntry{}, err
+	}
+
+	var nlink uint64
+	var uid, gid uint32
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		nlink = uint64(stat.Nlink)
+		uid = stat.Uid
+		gid = stat.Gid
+	}
+
+	size := strconv.FormatInt(info.Size(), 10)
+	if human {
+		size = humanSize(info.Size())
+	}
+
+	return longEntry{
+		mode:    info.Mode().String(),
+		nlink:   strconv.FormatUint(nlink, 10),
+		owner:   lookupUser(uid),
+		group:   lookupGroup(gid),
+		size:    size,
+		mtime:   info.ModTime().Format("Jan _2 15:04"),
+		display: renderEntry(dir, name, lsc),
+	}, nil
+}
+
+// humanSize formats n the way `ls -h` does: one decimal place past 1
+// KiB, unit letters K/M/G/T/P/E.
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func padLeft(s string, width int) string {
+	w := displayWidth(s)
+	if w >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-w) + s
+}
+
+func padRight(s string, width int) string {
+	w := displayWidth(s)
+	if w >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-w)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// listDirViaLS shells out to GNU ls for users who want its exact
+// behavior (BSD/macOS ls doesn't support --group-directories-first or
+// the same --color flag, so this path isn't portable). rawArgs is
+// parseArgs's leftovers: every flag ls-override recognizes itself
+// (--use-ls, -x, -l, --theme, ...) has already been stripped, since
+// those are ours, not the real ls's, and forwarding them would corrupt
+// its output or make it exit with "unrecognized option".
+func listDirViaLS(rawArgs []string) ([]string, error) {
+	cmd := exec.Command("ls", append(rawArgs, "--color=always", "-1", "-A", "-F", "--group-directories-first")...)
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting ls: %w", err)
+	}
+
+	var files []string
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		files = append(files, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading ls output: %w", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Recolor dotfiles
+	for i, f := range files {
+		nameStripped := stripANSI(f)
+		if strings.HasPrefix(nameStripped, ".") {
+			info, err := os.Stat(nameStripped)
+			if err == nil && info.IsDir() {
+				files[i] = nameColors["dotdir"] + nameStripped + "\033[0m"
+			} else {
+				// Only recolor if ls didn't color it
+				if !strings.Contains(f, "\x1b[") {
+					files[i] = nameColors["dotfile"] + nameStripped + "\033[0m"
+				}
+			}
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// lsColorSet holds a parsed LS_COLORS/LSCOLORS palette: escape codes
+// keyed by two-letter file type ("di", "ln", "ex", ...) and by
+// extension glob ("*.go" stored as ".go") mapped to raw SGR parameter
+// strings (e.g. "01;32").
+type lsColorSet struct {
+	types map[string]string
+	exts  map[string]string
+}
+
+var defaultLSColorCodes = map[string]string{
+	"di": "01;34",
+	"ln": "01;36",
+	"ex": "01;32",
+	"pi": "33",
+	"so": "01;35",
+	"bd": "40;33;01",
+	"cd": "40;33;01",
+	"su": "37;41",
+	"sg": "30;43",
+	"ow": "34;42",
+	"tw": "30;42",
+}
+
+// themeExtensions holds extension-glob overrides from the active theme
+// (config file or --theme preset), seeded into every lsColorSet before
+// $LS_COLORS/$LSCOLORS are applied on top.
+var themeExtensions = map[string]string{}
+
+// loadLSColors builds the active color set from $LS_COLORS (GNU
+// dircolors format) or $LSCOLORS (BSD format), falling back to GNU
+// ls's built-in defaults when neither is set.
+func loadLSColors() lsColorSet {
+	set := lsColorSet{types: map[string]string{}, exts: map[string]string{}}
+	for k, v := range defaultLSColorCodes {
+		set.types[k] = v
+	}
+	for k, v := range themeExtensions {
+		set.exts[k] = v
+	}
+
+	if gnu := os.Getenv("LS_COLORS"); gnu != "" {
+		set.applyGNU(gnu)
+		return set
+	}
+
+	if bsd := os.Getenv("LSCOLORS"); bsd != "" {
+		set.applyBSD(bsd)
+	}
+
+	return set
+}
+
+func (s *lsColorSet) applyGNU(spec string) {
+	for _, field := range strings.Split(spec, ":") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, val := kv[0], kv[1]
+		if strings.HasPrefix(key, "*") {
+			s.exts[strings.ToLower(key[1:])] = val
+		} else {
+			s.types[key] = val
+		}
+	}
+}
+
+// bsdFG/bsdBG map BSD LSCOLORS letters (lowercase a-h for foreground,
+// uppercase A-H for background, x/X for terminal default) to GNU-style
+// SGR foreground/background parameter numbers.
+var bsdFG = map[byte]string{
+	'a': "30", 'b': "31", 'c': "32", 'd': "33",
+	'e': "34", 'f': "35", 'g': "36", 'h': "37", 'x': "",
+}
+var bsdBG = map[byte]string{
+	'A': "40", 'B': "41", 'C': "42", 'D': "43",
+	'E': "44", 'F': "45", 'G': "46", 'H': "47", 'X': "",
+}
+
+// bsdSlots is LSCOLORS's fixed field order: directory, symlink, socket,
+// pipe, executable, block device, char device, setuid executable,
+// setgid executable, dir writable by others with sticky bit.
+var bsdSlots = []string{"di", "ln", "so", "pi", "ex", "bd", "cd", "su", "sg", "tw"}
+
+func (s *lsColorSet) applyBSD(spec string) {
+	for i, key := range bsdSlots {
+		fgIdx, bgIdx := i*2, i*2+1
+		if bgIdx >= len(spec) {
+			break
+		}
+		fg, bg := bsdFG[spec[fgIdx]], bsdBG[spec[bgIdx]]
+		var parts []string
+		if fg != "" {
+			parts = append(parts, fg)
+		}
+		if bg != "" {
+			parts = append(parts, bg)
+		}
+		if len(parts) > 0 {
+			s.types[key] = strings.Join(parts, ";")
+		}
+	}
+}
+
+// codeFor returns the raw SGR parameter string for name/mode. Directories,
+// symlinks, pipes, sockets, devices and setuid/setgid files take their
+// type color; otherwise an extension match wins over the executable
+// color, falling back to "fi" (plain file) when nothing matches.
+func (s *lsColorSet) codeFor(name string, mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return s.types["di"]
+	case mode&os.ModeSymlink != 0:
+		return s.types["ln"]
+	case mode&os.ModeNamedPipe != 0:
+		return s.types["pi"]
+	case mode&os.ModeSocket != 0:
+		return s.types["so"]
+	case mode&os.ModeDevice != 0:
+		if mode&os.ModeCharDevice != 0 {
+			return s.types["cd"]
+		}
+		return s.types["bd"]
+	case mode&os.ModeSetuid != 0:
+		return s.types["su"]
+	case mode&os.ModeSetgid != 0:
+		return s.types["sg"]
+	}
+
+	if ext, ok := s.extCode(name); ok {
+		return ext
+	}
+
+	if mode&0111 != 0 {
+		return s.types["ex"]
+	}
+
+	return s.types["fi"]
+}
+
+func (s *lsColorSet) extCode(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for ext, code := range s.exts {
+		if strings.HasSuffix(lower, ext) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// sgr wraps a raw SGR parameter string in its escape sequence, or
+// returns "" if code is empty.
+func sgr(code string) string {
+	if code == "" {
+		return ""
+	}
+	return "\x1b[" + code + "m"
+}
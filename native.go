@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// orderedEntries lists dir's entry names grouped with directories first
+// (mirroring --group-directories-first) and dotfiles included
+// (mirroring -A). Shared by every native rendering mode (grid, across,
+// long) so they always agree on ordering.
+func orderedEntries(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs, rest []os.DirEntry
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, e)
+		} else {
+			rest = append(rest, e)
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Name() < dirs[j].Name() })
+	sort.Slice(rest, func(i, j int) bool { return rest[i].Name() < rest[j].Name() })
+
+	names := make([]string, 0, len(dirs)+len(rest))
+	for _, e := range append(dirs, rest...) {
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+// listDirNative lists dir without shelling out to GNU ls, rendering
+// -F classifier suffixes plus LS_COLORS/LSCOLORS colorization for the
+// column-grid layouts.
+func listDirNative(dir string) ([]string, error) {
+	names, err := orderedEntries(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	lsc := loadLSColors()
+
+	files := make([]string, 0, len(names))
+	for _, name := range names {
+		files = append(files, renderEntry(dir, name, lsc))
+	}
+	return files, nil
+}
+
+// renderEntry applies the -F classifier suffix, symlink target, and
+// color for a single entry, honoring the nameColors overrides for
+// dotfiles/dotdirs the same way the ls-backed path does.
+func renderEntry(dir, name string, lsc lsColorSet) string {
+	full := filepath.Join(dir, name)
+	info, err := os.Lstat(full)
+	if err != nil {
+		return name
+	}
+	mode := info.Mode()
+
+	styled := sgr(lsc.codeFor(name, mode)) + name
+	if styled != name {
+		styled += "\033[0m"
+	}
+	if strings.HasPrefix(name, ".") {
+		switch {
+		case mode.IsDir():
+			styled = nameColors["dotdir"] + name + "\033[0m"
+		case lsc.codeFor(name, mode) == "":
+			styled = nameColors["dotfile"] + name + "\033[0m"
+		}
+	}
+
+	rendered := gitStatusPrefix(name, mode.IsDir()) + wrapHyperlink(name, styled) + classifier(mode)
+	if mode&os.ModeSymlink != 0 {
+		if target, err := os.Readlink(full); err == nil {
+			rendered += " -> " + target
+		}
+	}
+	return rendered
+}
+
+// classifier returns the -F suffix for mode: "/" dirs, "*" executables,
+// "@" symlinks, "|" FIFOs, "=" sockets.
+func classifier(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "/"
+	case mode&os.ModeSymlink != 0:
+		return "@"
+	case mode&os.ModeNamedPipe != 0:
+		return "|"
+	case mode&os.ModeSocket != 0:
+		return "="
+	case mode&0111 != 0:
+		return "*"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// namedColorCodes maps the 8/16-color names accepted in a theme file to
+// their SGR foreground parameter.
+var namedColorCodes = map[string]string{
+	"black": "30", "red": "31", "green": "32", "yellow": "33",
+	"blue": "34", "magenta": "35", "cyan": "36", "white": "37",
+	"gray": "90", "grey": "90",
+	"brightred": "91", "brightgreen": "92", "brightyellow": "93",
+	"brightblue": "94", "brightmagenta": "95", "brightcyan": "96", "brightwhite": "97",
+}
+
+var rawSGRPattern = regexp.MustCompile(`^[0-9]+(;[0-9]+)*$`)
+
+// resolveColor turns a theme color value into a raw SGR parameter
+// string: "#rrggbb" expands to a 24-bit escape, a name looks up
+// namedColorCodes, and an already-numeric SGR string (e.g. "01;32") is
+// passed through for users who want to match LS_COLORS syntax directly.
+func resolveColor(value string) (string, error) {
+	v := strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(v, "#"):
+		return hexToSGR(v)
+	case rawSGRPattern.MatchString(v):
+		return v, nil
+	default:
+		if code, ok := namedColorCodes[strings.ToLower(v)]; ok {
+			return code, nil
+		}
+		return "", fmt.Errorf("unrecognized color %q", value)
+	}
+}
+
+func hexToSGR(v string) (string, error) {
+	if len(v) != 7 {
+		return "", fmt.Errorf("invalid hex color %q (want #rrggbb)", v)
+	}
+	r, errR := strconv.ParseUint(v[1:3], 16, 8)
+	g, errG := strconv.ParseUint(v[3:5], 16, 8)
+	b, errB := strconv.ParseUint(v[5:7], 16, 8)
+	if errR != nil || errG != nil || errB != nil {
+		return "", fmt.Errorf("invalid hex color %q", v)
+	}
+	return fmt.Sprintf("38;2;%d;%d;%d", r, g, b), nil
+}
+
+// resolvedTheme is a themeFile after its color strings have been
+// validated and reduced to raw SGR parameters.
+type resolvedTheme struct {
+	dotdir, dotfile, executable, symlink, socket, setuid string
+	extensions                                           map[string]string
+}
+
+// resolveTheme validates every color in tf, returning a clear error
+// naming the offending key on the first failure.
+func resolveTheme(tf themeFile) (resolvedTheme, error) {
+	rt := resolvedTheme{extensions: map[string]string{}}
+
+	fields := []struct {
+		key   string
+		value string
+		dest  *string
+	}{
+		{"colors.dotdir", tf.Colors.Dotdir, &rt.dotdir},
+		{"colors.dotfile", tf.Colors.Dotfile, &rt.dotfile},
+		{"colors.executable", tf.Colors.Executable, &rt.executable},
+		{"colors.symlink", tf.Colors.Symlink, &rt.symlink},
+		{"colors.socket", tf.Colors.Socket, &rt.socket},
+		{"colors.setuid", tf.Colors.Setuid, &rt.setuid},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		code, err := resolveColor(f.value)
+		if err != nil {
+			return resolvedTheme{}, fmt.Errorf("%s: %w", f.key, err)
+		}
+		*f.dest = code
+	}
+
+	for glob, value := range tf.Extensions {
+		code, err := resolveColor(value)
+		if err != nil {
+			return resolvedTheme{}, fmt.Errorf("extensions[%q]: %w", glob, err)
+		}
+		rt.extensions[strings.ToLower(strings.TrimPrefix(glob, "*"))] = code
+	}
+
+	return rt, nil
+}
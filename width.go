@@ -0,0 +1,66 @@
+package main
+
+import "unicode/utf8"
+
+// wideRanges lists the rune ranges treated as double-width (East Asian
+// Wide/Fullwidth, plus the common emoji blocks). It is not exhaustive,
+// but it covers the characters users actually hit in filenames.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK Radicals, Kangxi, CJK symbols/punctuation
+	{0x3041, 0x33FF},   // Hiragana .. CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi syllables/radicals
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x1F300, 0x1FAFF}, // emoji & pictographs
+	{0x20000, 0x3FFFD}, // CJK extensions B..
+}
+
+// combiningRanges lists zero-width combining marks.
+var combiningRanges = [][2]rune{
+	{0x0300, 0x036F}, // combining diacritical marks
+	{0x200B, 0x200F}, // zero width space/joiners, direction marks
+	{0x20D0, 0x20FF}, // combining diacritical marks for symbols
+	{0xFE00, 0xFE0F}, // variation selectors
+	{0xFE20, 0xFE2F}, // combining half marks
+}
+
+func inRanges(r rune, ranges [][2]rune) bool {
+	for _, rg := range ranges {
+		if r >= rg[0] && r <= rg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// runeWidth returns the terminal display width of a single rune: 0 for
+// combining marks, 2 for East Asian Wide/Fullwidth and emoji, 1 otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case inRanges(r, combiningRanges):
+		return 0
+	case inRanges(r, wideRanges):
+		return 2
+	default:
+		return 1
+	}
+}
+
+// displayWidth returns the terminal column width of s, decoding runes
+// rather than counting bytes so multi-byte UTF-8 (CJK, accents, emoji)
+// lines up correctly in column layouts.
+func displayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+// A name wrapped in two OSC 8 sequences (the opening link plus the
+// closing one wrapHyperlink emits) must strip to zero-width extra
+// escape bytes so column math isn't thrown off by hyperlinked entries.
+func TestDisplayWidthIgnoresHyperlinkWrapper(t *testing.T) {
+	name := "foo.txt"
+	wrapped := "\x1b]8;;file://host/abs/foo.txt\x1b\\" + name + "\x1b]8;;\x1b\\"
+
+	got := displayWidth(stripANSI(wrapped))
+	if want := displayWidth(name); got != want {
+		t.Fatalf("displayWidth(stripANSI(wrapped)) = %d, want %d (stripped=%q)", got, want, stripANSI(wrapped))
+	}
+}
+
+func TestRuneWidth(t *testing.T) {
+	cases := []struct {
+		name string
+		r    rune
+		want int
+	}{
+		{"combining acute accent", '́', 0},
+		{"variation selector", '️', 0},
+		{"cjk ideograph", '中', 2},   // 中
+		{"hangul syllable", '가', 2}, // 가
+		{"emoji", '\U0001F600', 2},  // 😀
+		{"fullwidth latin", 'Ａ', 2}, // Ａ
+		{"ascii letter", 'a', 1},
+		{"ascii digit", '5', 1},
+	}
+	for _, c := range cases {
+		if got := runeWidth(c.r); got != c.want {
+			t.Errorf("%s: runeWidth(%U) = %d, want %d", c.name, c.r, got, c.want)
+		}
+	}
+}
+
+func TestDisplayWidthMixedStrings(t *testing.T) {
+	cases := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"plain ascii", "report.txt", 10},
+		{"cjk filename", "中文.txt", 2 + 2 + 4},                    // 中文.txt
+		{"emoji filename", "\U0001F389party.txt", 2 + 9},         // 🎉party.txt
+		{"combining mark adds no width", "café.txt", 4 + 0 + 4}, // café (decomposed).txt
+	}
+	for _, c := range cases {
+		if got := displayWidth(c.s); got != c.want {
+			t.Errorf("%s: displayWidth(%q) = %d, want %d", c.name, c.s, got, c.want)
+		}
+	}
+}